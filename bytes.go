@@ -0,0 +1,130 @@
+package kingpin
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Bytes sets the parser to a human-readable byte size parser, accepting
+// plain numbers as well as SI (kB, MB, GB, TB) and IEC (KiB, MiB, GiB, TiB)
+// suffixes, e.g. "512", "4k", "4KiB", "1.5GB", "2Gi".
+func (p *parserMixin) Bytes() (target *uint64) {
+	target = new(uint64)
+	p.BytesVar(target)
+	return
+}
+
+// BytesVar is like Bytes but stores into an existing variable.
+func (p *parserMixin) BytesVar(target *uint64) {
+	p.SetValue(newBytesValue(target))
+}
+
+// byteUnits is checked in order, so multi-letter suffixes (e.g. "kib") are
+// matched before the single-letter ones they'd otherwise be mistaken for
+// (e.g. "b").
+var byteUnits = []struct {
+	suffix     string
+	multiplier uint64
+}{
+	{"tib", 1 << 40},
+	{"ti", 1 << 40},
+	{"tb", 1000 * 1000 * 1000 * 1000},
+	{"t", 1000 * 1000 * 1000 * 1000},
+	{"gib", 1 << 30},
+	{"gi", 1 << 30},
+	{"gb", 1000 * 1000 * 1000},
+	{"g", 1000 * 1000 * 1000},
+	{"mib", 1 << 20},
+	{"mi", 1 << 20},
+	{"mb", 1000 * 1000},
+	{"m", 1000 * 1000},
+	{"kib", 1 << 10},
+	{"ki", 1 << 10},
+	{"kb", 1000},
+	{"k", 1000},
+	{"b", 1},
+}
+
+// iecSuffixes is checked from largest to smallest so String renders a value
+// with the largest unit that divides it exactly (1GiB, not 1048576KiB).
+var iecSuffixes = []struct {
+	suffix string
+	size   uint64
+}{
+	{"TiB", 1 << 40},
+	{"GiB", 1 << 30},
+	{"MiB", 1 << 20},
+	{"KiB", 1 << 10},
+}
+
+// -- byte-size Value
+
+type bytesValue uint64
+
+func newBytesValue(target *uint64) *bytesValue {
+	return (*bytesValue)(target)
+}
+
+// maxUint64AsFloat is 2^64, the exclusive upper bound a float64 result must
+// stay under to convert to uint64 without overflowing. It's exactly
+// representable in float64, unlike math.MaxUint64 itself, so comparing
+// against it (rather than against float64(^uint64(0))) doesn't round away
+// the boundary it's meant to catch.
+const maxUint64AsFloat = 1 << 64
+
+func (b *bytesValue) Set(value string) error {
+	trimmed := strings.TrimSpace(value)
+	lower := strings.ToLower(trimmed)
+	numeric := trimmed
+	multiplier := uint64(1)
+	for _, u := range byteUnits {
+		if strings.HasSuffix(lower, u.suffix) {
+			multiplier = u.multiplier
+			numeric = strings.TrimSpace(trimmed[:len(trimmed)-len(u.suffix)])
+			break
+		}
+	}
+
+	// Plain integers (no unit, no decimal point) parse directly as uint64,
+	// avoiding the float64 precision loss that a multiply-then-bound-check
+	// would suffer right at the uint64 boundary.
+	if multiplier == 1 && !strings.ContainsAny(numeric, ".eE") {
+		n, err := strconv.ParseUint(numeric, 10, 64)
+		if err != nil {
+			return fmt.Errorf("'%s' is not a valid byte size", value)
+		}
+		*b = bytesValue(n)
+		return nil
+	}
+
+	n, err := strconv.ParseFloat(numeric, 64)
+	if err != nil {
+		return fmt.Errorf("'%s' is not a valid byte size", value)
+	}
+	if n < 0 {
+		return fmt.Errorf("byte size '%s' must not be negative", value)
+	}
+	result := n * float64(multiplier)
+	if result >= maxUint64AsFloat {
+		return fmt.Errorf("byte size '%s' overflows", value)
+	}
+	*b = bytesValue(result)
+	return nil
+}
+
+func (b *bytesValue) Get() interface{} {
+	return (uint64)(*b)
+}
+
+// String renders the value using the largest IEC unit that divides it
+// exactly, falling back to a plain byte count.
+func (b *bytesValue) String() string {
+	n := uint64(*b)
+	for _, u := range iecSuffixes {
+		if n != 0 && n%u.size == 0 {
+			return fmt.Sprintf("%d%s", n/u.size, u.suffix)
+		}
+	}
+	return fmt.Sprintf("%dB", n)
+}
@@ -0,0 +1,61 @@
+package kingpin
+
+import "testing"
+
+func TestBytesValueSet(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    uint64
+		wantErr bool
+	}{
+		{in: "512", want: 512},
+		{in: "4k", want: 4000},
+		{in: "4KiB", want: 4096},
+		{in: "1.5GB", want: 1500000000},
+		{in: "2Gi", want: 2 * (1 << 30)},
+		{in: "0", want: 0},
+		{in: "18446744073709551615", want: 18446744073709551615}, // math.MaxUint64: valid, not an overflow
+		{in: "18446744073709551616", wantErr: true},              // one past math.MaxUint64: overflows
+		{in: "-1", wantErr: true},
+		{in: "-1k", wantErr: true},
+		{in: "not-a-size", wantErr: true},
+	}
+	for _, tt := range tests {
+		var target uint64
+		v := newBytesValue(&target)
+		err := v.Set(tt.in)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("Set(%q): expected error, got none (value=%d)", tt.in, target)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Set(%q): unexpected error: %s", tt.in, err)
+			continue
+		}
+		if target != tt.want {
+			t.Errorf("Set(%q) = %d, want %d", tt.in, target, tt.want)
+		}
+	}
+}
+
+func TestBytesValueString(t *testing.T) {
+	tests := []struct {
+		in   uint64
+		want string
+	}{
+		{in: 0, want: "0B"},
+		{in: 512, want: "512B"},
+		{in: 1024, want: "1KiB"},
+		{in: 4096, want: "4KiB"},
+		{in: 1 << 30, want: "1GiB"},
+		{in: 1500000000, want: "1500000000B"}, // not an exact IEC multiple
+	}
+	for _, tt := range tests {
+		v := bytesValue(tt.in)
+		if got := v.String(); got != tt.want {
+			t.Errorf("bytesValue(%d).String() = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,386 @@
+package kingpin
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+)
+
+// boolFlag is implemented by values (such as Bool or Counter) that take no
+// argument on the command line; Parse treats their mere presence as "true"
+// rather than consuming the next argument.
+type boolFlag interface {
+	Value
+	IsBoolFlag() bool
+}
+
+// Config is the generic key/value tree decoded from a configuration file,
+// addressed by dotted path (e.g. "server.listen.addr").
+type Config struct {
+	values map[string]interface{}
+}
+
+// ConfigDecoder turns raw configuration file bytes into a generic
+// key/value tree. JSON, YAML and TOML are supported out of the box (see
+// config_yaml_toml.go for the pragmatic subsets of YAML/TOML covered);
+// register a decoder for another format, or a fuller implementation of
+// these, with RegisterConfigDecoder so this package doesn't need to depend
+// on third-party parsers directly.
+type ConfigDecoder func(data []byte) (map[string]interface{}, error)
+
+var configDecoders = map[string]ConfigDecoder{
+	".json": decodeJSONConfig,
+}
+
+func decodeJSONConfig(data []byte) (map[string]interface{}, error) {
+	out := map[string]interface{}{}
+	if len(strings.TrimSpace(string(data))) == 0 {
+		return out, nil
+	}
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// RegisterConfigDecoder associates a configuration file extension
+// (including the leading dot, e.g. ".yaml") with a decoder, so
+// Application.ConfigFile can load formats beyond the built-in JSON, YAML
+// and TOML support, or replace one of those built-in decoders.
+func RegisterConfigDecoder(ext string, decode ConfigDecoder) {
+	configDecoders[ext] = decode
+}
+
+func loadConfig(path string) (*Config, error) {
+	ext := strings.ToLower(configExt(path))
+	decode, ok := configDecoders[ext]
+	if !ok {
+		return nil, fmt.Errorf("kingpin: no config decoder registered for %q files", ext)
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	values, err := decode(data)
+	if err != nil {
+		return nil, fmt.Errorf("kingpin: parsing %s: %s", path, err)
+	}
+	return &Config{values: values}, nil
+}
+
+func configExt(path string) string {
+	if i := strings.LastIndexByte(path, '.'); i >= 0 {
+		return path[i:]
+	}
+	return ""
+}
+
+// lookup resolves a dotted path against the decoded config tree, returning
+// the raw decoded value (string, float64, []interface{}, ...).
+func (c *Config) lookup(path string) (interface{}, bool) {
+	if c == nil {
+		return nil, false
+	}
+	var cur interface{} = c.values
+	for _, key := range strings.Split(path, ".") {
+		m, ok := cur.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		cur, ok = m[key]
+		if !ok {
+			return nil, false
+		}
+	}
+	return cur, true
+}
+
+// Application is the top-level command-line parser. Flags registered
+// against it may additionally be satisfied by an environment variable
+// (parserMixin.Envar) or a configuration file: map a flag to a key with
+// parserMixin.ConfigPath, then point the application at the file with
+// ConfigFile. Precedence is CLI > env > config file > default, and a
+// required flag satisfied only by its environment variable or the config
+// file is not considered missing.
+type Application struct {
+	Name string
+	Help string
+
+	configPath string
+	config     *Config
+	mixins     []*parserMixin
+	terminate  func(status int)
+}
+
+// New creates a new command-line application.
+func New(name, help string) *Application {
+	return &Application{Name: name, Help: help, terminate: os.Exit}
+}
+
+// Terminate overrides how Parse ends the process after a terminal action
+// such as --dump-config (os.Exit by default), so tests can intercept it
+// instead of exiting.
+func (a *Application) Terminate(terminate func(status int)) *Application {
+	a.terminate = terminate
+	return a
+}
+
+// ConfigFile sets the path to a configuration file used to satisfy flag
+// values not given on the command line or through an environment variable.
+// The format is selected by the file's extension; see RegisterConfigDecoder.
+func (a *Application) ConfigFile(path string) *Application {
+	a.configPath = path
+	return a
+}
+
+// ConfigFileVar exposes the parsed configuration file to application code
+// by storing it in target at the time of the call. Parse loads the file
+// whenever ConfigFile was set, so a call made before Parse stores nil; a
+// later Parse does not update target.
+func (a *Application) ConfigFileVar(target **Config) {
+	*target = a.config
+}
+
+// DumpConfig is retained for backward compatibility; --dump-config is
+// always recognized by Parse and no longer needs to be opted into. It is a
+// no-op and may be dropped from call sites.
+func (a *Application) DumpConfig() *Application {
+	return a
+}
+
+// track records a flag so its ConfigPath (if any) can be resolved once the
+// configuration file is loaded, and so Parse can enforce that it's required.
+func (a *Application) track(p *parserMixin) {
+	a.mixins = append(a.mixins, p)
+}
+
+// Flag defines a new flag owned by this application, named name for use on
+// the command line (as --name) and in Parse's error messages; help is a
+// one-line description. Call a parser method (String,
+// Int, CIDR, Secret, ...) on the returned mixin to select the flag's type.
+func (a *Application) Flag(name, help string) *parserMixin {
+	p := &parserMixin{name: name, help: help}
+	a.track(p)
+	return p
+}
+
+// Parse parses command-line arguments against the application's flags,
+// resolves any still-unset flags from their environment variable and then
+// their ConfigPath in the configuration file, and enforces that every
+// required flag was satisfied by one of those sources. It returns the
+// positional (non-flag) arguments.
+func (a *Application) Parse(args []string) ([]string, error) {
+	byName := map[string]*parserMixin{}
+	byShort := map[byte]*parserMixin{}
+	for _, p := range a.mixins {
+		if p.name != "" {
+			byName[p.name] = p
+		}
+		if p.short != 0 {
+			byShort[p.short] = p
+		}
+	}
+
+	resolved := map[*parserMixin]bool{}
+	var positional []string
+	dumpConfig := false
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--dump-config" {
+			dumpConfig = true
+			continue
+		}
+		if strings.HasPrefix(arg, "--") {
+			name, value, hasValue := strings.TrimPrefix(arg, "--"), "", false
+			if idx := strings.IndexByte(name, '='); idx >= 0 {
+				name, value, hasValue = name[:idx], name[idx+1:], true
+			}
+			p, ok := byName[name]
+			if !ok && !hasValue && strings.HasPrefix(name, "no-") {
+				// --no-<X> sets a bool flag <X> to false.
+				if np, found := byName[strings.TrimPrefix(name, "no-")]; found {
+					if bf, isBool := np.value.(boolFlag); isBool && bf.IsBoolFlag() {
+						p, ok, value, hasValue = np, true, "false", true
+					}
+				}
+			}
+			if !ok {
+				return nil, fmt.Errorf("kingpin: unknown flag --%s", name)
+			}
+			if !hasValue {
+				if bf, isBool := p.value.(boolFlag); isBool && bf.IsBoolFlag() {
+					value = "true"
+				} else {
+					i++
+					if i >= len(args) {
+						return nil, fmt.Errorf("kingpin: expected argument for flag --%s", name)
+					}
+					value = args[i]
+				}
+			}
+			if err := p.value.Set(value); err != nil {
+				return nil, fmt.Errorf("kingpin: --%s: %s", name, err)
+			}
+			resolved[p] = true
+			continue
+		}
+		if len(arg) > 1 && arg[0] == '-' {
+			// A run of short flags, e.g. -v, or bundled as -vvv. Each
+			// bool-flag letter (IsBoolFlag) consumes no argument, so
+			// several can be bundled in a row; the first non-bool-flag
+			// letter takes the rest of the bundle, or the next arg, as
+			// its value and ends the run.
+			letters := arg[1:]
+			for j := 0; j < len(letters); j++ {
+				c := letters[j]
+				p, ok := byShort[c]
+				if !ok {
+					return nil, fmt.Errorf("kingpin: unknown flag -%c", c)
+				}
+				if bf, isBool := p.value.(boolFlag); isBool && bf.IsBoolFlag() {
+					if err := p.value.Set("true"); err != nil {
+						return nil, fmt.Errorf("kingpin: -%c: %s", c, err)
+					}
+					resolved[p] = true
+					continue
+				}
+				var value string
+				if j+1 < len(letters) {
+					value = letters[j+1:]
+					j = len(letters)
+				} else {
+					i++
+					if i >= len(args) {
+						return nil, fmt.Errorf("kingpin: expected argument for flag -%c", c)
+					}
+					value = args[i]
+				}
+				if err := p.value.Set(value); err != nil {
+					return nil, fmt.Errorf("kingpin: -%c: %s", c, err)
+				}
+				resolved[p] = true
+			}
+			continue
+		}
+		positional = append(positional, arg)
+	}
+
+	if err := a.resolveEnv(resolved); err != nil {
+		return nil, err
+	}
+
+	if err := a.resolveConfig(resolved); err != nil {
+		return nil, err
+	}
+
+	if dumpConfig {
+		out, err := a.dumpConfigJSON()
+		if err != nil {
+			return nil, err
+		}
+		fmt.Println(string(out))
+		a.terminate(0)
+		return positional, nil
+	}
+
+	for _, p := range a.mixins {
+		if p.required && !resolved[p] {
+			return nil, fmt.Errorf("kingpin: required flag --%s not provided", p.name)
+		}
+	}
+
+	return positional, nil
+}
+
+// resolveEnv applies each tracked flag's environment variable, if it has
+// one set via parserMixin.Envar and was not already satisfied from the
+// command line (as recorded in resolved).
+func (a *Application) resolveEnv(resolved map[*parserMixin]bool) error {
+	for _, p := range a.mixins {
+		if resolved[p] || p.envar == "" {
+			continue
+		}
+		value, ok := os.LookupEnv(p.envar)
+		if !ok {
+			continue
+		}
+		if err := p.value.Set(value); err != nil {
+			return fmt.Errorf("kingpin: $%s: %s", p.envar, err)
+		}
+		resolved[p] = true
+	}
+	return nil
+}
+
+// resolveConfig loads the configuration file, if one was set, and applies
+// its values to every tracked flag whose ConfigPath is set and that was
+// not already satisfied from the command line or its environment variable
+// (as recorded in resolved).
+func (a *Application) resolveConfig(resolved map[*parserMixin]bool) error {
+	if a.configPath == "" {
+		return nil
+	}
+	if a.config == nil {
+		cfg, err := loadConfig(a.configPath)
+		if err != nil {
+			return err
+		}
+		a.config = cfg
+	}
+	for _, p := range a.mixins {
+		if resolved[p] || p.configPath == "" {
+			continue
+		}
+		raw, ok := a.config.lookup(p.configPath)
+		if !ok {
+			continue
+		}
+		if err := applyConfigValue(p.value, raw); err != nil {
+			return fmt.Errorf("kingpin: config %s: %s", p.configPath, err)
+		}
+		resolved[p] = true
+	}
+	return nil
+}
+
+// applyConfigValue feeds a decoded config value into a flag's Value. A
+// decoded list is applied element-by-element, which merges naturally into
+// cumulative (slice) flags since each Set call on them appends. A decoded
+// object is applied entry-by-entry as "key=value", the syntax StringMap's
+// Set expects, rather than being stringified wholesale. Any other flag
+// type overwrites on the second element like a repeated CLI flag would.
+func applyConfigValue(v Value, raw interface{}) error {
+	switch val := raw.(type) {
+	case []interface{}:
+		for _, item := range val {
+			if err := v.Set(fmt.Sprint(item)); err != nil {
+				return err
+			}
+		}
+		return nil
+	case map[string]interface{}:
+		for key, item := range val {
+			if err := v.Set(fmt.Sprintf("%s=%s", key, fmt.Sprint(item))); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		return v.Set(fmt.Sprint(raw))
+	}
+}
+
+// dumpConfigJSON renders the currently effective configuration as JSON, for
+// --dump-config.
+func (a *Application) dumpConfigJSON() ([]byte, error) {
+	out := map[string]interface{}{}
+	for _, p := range a.mixins {
+		if p.configPath == "" {
+			continue
+		}
+		out[p.configPath] = p.value.String()
+	}
+	return json.MarshalIndent(out, "", "  ")
+}
@@ -0,0 +1,301 @@
+package kingpin
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDecodeJSONConfig(t *testing.T) {
+	values, err := decodeJSONConfig([]byte(`{"server":{"listen":{"addr":"0.0.0.0:8080"}}}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	cfg := &Config{values: values}
+	got, ok := cfg.lookup("server.listen.addr")
+	if !ok || got != "0.0.0.0:8080" {
+		t.Errorf("lookup(server.listen.addr) = %v, %v, want \"0.0.0.0:8080\", true", got, ok)
+	}
+}
+
+func TestDecodeYAMLConfig(t *testing.T) {
+	data := []byte("server:\n  listen:\n    addr: 0.0.0.0:8080\n  hosts: [a, b, c]\n")
+	values, err := decodeYAMLConfig(data)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	cfg := &Config{values: values}
+
+	if got, ok := cfg.lookup("server.listen.addr"); !ok || got != "0.0.0.0:8080" {
+		t.Errorf("lookup(server.listen.addr) = %v, %v", got, ok)
+	}
+	hosts, ok := cfg.lookup("server.hosts")
+	if !ok {
+		t.Fatalf("lookup(server.hosts) not found")
+	}
+	list, ok := hosts.([]interface{})
+	if !ok || len(list) != 3 {
+		t.Errorf("lookup(server.hosts) = %v, want a 3-element list", hosts)
+	}
+}
+
+func TestDecodeYAMLConfigBlockStyleList(t *testing.T) {
+	data := []byte("server:\n  hosts:\n    - a\n    - b\n    - c\n  addr: 0.0.0.0:8080\n")
+	values, err := decodeYAMLConfig(data)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	cfg := &Config{values: values}
+
+	hosts, ok := cfg.lookup("server.hosts")
+	if !ok {
+		t.Fatalf("lookup(server.hosts) not found")
+	}
+	list, ok := hosts.([]interface{})
+	if !ok || len(list) != 3 {
+		t.Fatalf("lookup(server.hosts) = %v, want a 3-element list", hosts)
+	}
+	if list[0] != "a" || list[1] != "b" || list[2] != "c" {
+		t.Errorf("lookup(server.hosts) = %v, want [a b c]", list)
+	}
+	if got, ok := cfg.lookup("server.addr"); !ok || got != "0.0.0.0:8080" {
+		t.Errorf("lookup(server.addr) = %v, %v", got, ok)
+	}
+}
+
+func TestDecodeYAMLConfigBlockStyleListSameIndentAsKey(t *testing.T) {
+	// The idiomatic style for top-level YAML lists (Kubernetes, Ansible,
+	// ...): list items sit at the same indentation as their key, not
+	// indented further under it.
+	data := []byte("hosts:\n- a\n- b\naddr: 0.0.0.0:8080\n")
+	values, err := decodeYAMLConfig(data)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	cfg := &Config{values: values}
+
+	hosts, ok := cfg.lookup("hosts")
+	if !ok {
+		t.Fatalf("lookup(hosts) not found")
+	}
+	list, ok := hosts.([]interface{})
+	if !ok || len(list) != 2 || list[0] != "a" || list[1] != "b" {
+		t.Errorf("lookup(hosts) = %v, want [a b]", hosts)
+	}
+	if got, ok := cfg.lookup("addr"); !ok || got != "0.0.0.0:8080" {
+		t.Errorf("lookup(addr) = %v, %v", got, ok)
+	}
+}
+
+func TestDecodeYAMLConfigRejectsTrailingUnconsumedLines(t *testing.T) {
+	// A line more indented than its preceding scalar-valued key belongs
+	// to nothing and must be an error, not silently dropped.
+	data := []byte("addr: x\n  - orphan\n")
+	if _, err := decodeYAMLConfig(data); err == nil {
+		t.Error("decodeYAMLConfig: expected error for an orphaned indented line, got none")
+	}
+}
+
+func TestDecodeTOMLConfig(t *testing.T) {
+	data := []byte("[server.listen]\naddr = \"0.0.0.0:8080\"\n\n[server]\nhosts = [\"a\", \"b\"]\n")
+	values, err := decodeTOMLConfig(data)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	cfg := &Config{values: values}
+
+	if got, ok := cfg.lookup("server.listen.addr"); !ok || got != "0.0.0.0:8080" {
+		t.Errorf("lookup(server.listen.addr) = %v, %v", got, ok)
+	}
+	if got, ok := cfg.lookup("server.hosts"); !ok {
+		t.Errorf("lookup(server.hosts) not found, got %v", got)
+	}
+}
+
+func TestApplyConfigValueMergesObjectIntoKeyEqualsValue(t *testing.T) {
+	target := map[string]string{}
+	v := newStringMapValue(&target)
+
+	raw := map[string]interface{}{"region": "us-east-1"}
+	if err := applyConfigValue(v, raw); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if target["region"] != "us-east-1" {
+		t.Errorf("target = %v, want region=us-east-1", target)
+	}
+}
+
+func TestApplyConfigValueAppliesListElementByElement(t *testing.T) {
+	var target []string
+	v := newStringsValue(&target)
+
+	raw := []interface{}{"a", "b", "c"}
+	if err := applyConfigValue(v, raw); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(target) != 3 {
+		t.Errorf("target = %v, want 3 elements", target)
+	}
+}
+
+func TestApplicationParseConfigPrecedence(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.json")
+	if err := os.WriteFile(path, []byte(`{"addr":"from-config"}`), 0o600); err != nil {
+		t.Fatalf("writing fixture: %s", err)
+	}
+
+	app := New("test", "").ConfigFile(path)
+	f := app.Flag("addr", "listen address")
+	f.required = true
+	f.ConfigPath("addr")
+	addr := f.String()
+
+	if _, err := app.Parse(nil); err != nil {
+		t.Fatalf("Parse: unexpected error: %s", err)
+	}
+	if *addr != "from-config" {
+		t.Errorf("addr = %q, want %q (config file should satisfy a required flag)", *addr, "from-config")
+	}
+}
+
+func TestApplicationParseCLIOverridesConfig(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.json")
+	if err := os.WriteFile(path, []byte(`{"addr":"from-config"}`), 0o600); err != nil {
+		t.Fatalf("writing fixture: %s", err)
+	}
+
+	app := New("test", "").ConfigFile(path)
+	f := app.Flag("addr", "listen address")
+	f.ConfigPath("addr")
+	addr := f.String()
+
+	if _, err := app.Parse([]string{"--addr=from-cli"}); err != nil {
+		t.Fatalf("Parse: unexpected error: %s", err)
+	}
+	if *addr != "from-cli" {
+		t.Errorf("addr = %q, want %q (CLI must win over config file)", *addr, "from-cli")
+	}
+}
+
+func TestApplicationParseMissingRequiredFlag(t *testing.T) {
+	app := New("test", "")
+	f := app.Flag("addr", "listen address")
+	f.required = true
+	_ = f.String()
+
+	if _, err := app.Parse(nil); err == nil {
+		t.Error("Parse: expected error for missing required flag, got none")
+	}
+}
+
+func TestApplicationDumpConfigThenParseOrdinaryArgs(t *testing.T) {
+	// DumpConfig is a no-op: calling it must not leave the application
+	// permanently stuck in the dump-and-terminate branch of Parse.
+	app := New("test", "").DumpConfig()
+	var terminated bool
+	app.Terminate(func(status int) { terminated = true })
+
+	addr := app.Flag("addr", "listen address").String()
+
+	if _, err := app.Parse([]string{"--addr=1.2.3.4"}); err != nil {
+		t.Fatalf("Parse: unexpected error: %s", err)
+	}
+	if *addr != "1.2.3.4" {
+		t.Errorf("addr = %q, want %q", *addr, "1.2.3.4")
+	}
+	if terminated {
+		t.Error("Parse: Terminate was called, but --dump-config was never given")
+	}
+}
+
+func TestApplicationParseEnvarPrecedence(t *testing.T) {
+	t.Setenv("KINGPIN_TEST_ADDR", "from-env")
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.json")
+	if err := os.WriteFile(path, []byte(`{"addr":"from-config"}`), 0o600); err != nil {
+		t.Fatalf("writing fixture: %s", err)
+	}
+
+	app := New("test", "").ConfigFile(path)
+	f := app.Flag("addr", "listen address")
+	f.Envar("KINGPIN_TEST_ADDR")
+	f.ConfigPath("addr")
+	addr := f.String()
+
+	if _, err := app.Parse(nil); err != nil {
+		t.Fatalf("Parse: unexpected error: %s", err)
+	}
+	if *addr != "from-env" {
+		t.Errorf("addr = %q, want %q (env must win over config file)", *addr, "from-env")
+	}
+}
+
+func TestApplicationParseCLIOverridesEnvar(t *testing.T) {
+	t.Setenv("KINGPIN_TEST_ADDR", "from-env")
+
+	app := New("test", "")
+	f := app.Flag("addr", "listen address")
+	f.Envar("KINGPIN_TEST_ADDR")
+	addr := f.String()
+
+	if _, err := app.Parse([]string{"--addr=from-cli"}); err != nil {
+		t.Fatalf("Parse: unexpected error: %s", err)
+	}
+	if *addr != "from-cli" {
+		t.Errorf("addr = %q, want %q (CLI must win over env)", *addr, "from-cli")
+	}
+}
+
+func TestApplicationParseShortFlagBundling(t *testing.T) {
+	app := New("test", "")
+	var n int
+	app.Flag("verbose", "verbosity").Short('v').CounterVar(&n)
+
+	if _, err := app.Parse([]string{"-v", "-v", "-v"}); err != nil {
+		t.Fatalf("Parse(-v -v -v): unexpected error: %s", err)
+	}
+	if n != 3 {
+		t.Errorf("n = %d, want 3 (three separate -v occurrences)", n)
+	}
+
+	n = 0
+	if _, err := app.Parse([]string{"-vvv"}); err != nil {
+		t.Fatalf("Parse(-vvv): unexpected error: %s", err)
+	}
+	if n != 3 {
+		t.Errorf("n = %d, want 3 (bundled -vvv)", n)
+	}
+}
+
+func TestApplicationParseDumpConfig(t *testing.T) {
+	app := New("test", "")
+	var terminated bool
+	var terminatedStatus int
+	app.Terminate(func(status int) { terminated = true; terminatedStatus = status })
+
+	f := app.Flag("addr", "listen address")
+	f.ConfigPath("addr")
+	addr := f.String()
+	*addr = "0.0.0.0:8080"
+
+	if _, err := app.Parse([]string{"--dump-config"}); err != nil {
+		t.Fatalf("Parse: unexpected error: %s", err)
+	}
+	if !terminated {
+		t.Error("Parse(--dump-config): expected Terminate to be called, it wasn't")
+	}
+	if terminatedStatus != 0 {
+		t.Errorf("Terminate called with status %d, want 0", terminatedStatus)
+	}
+
+	out, err := app.dumpConfigJSON()
+	if err != nil {
+		t.Fatalf("dumpConfigJSON: unexpected error: %s", err)
+	}
+	if len(out) == 0 {
+		t.Error("dumpConfigJSON: expected non-empty output")
+	}
+}
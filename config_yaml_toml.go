@@ -0,0 +1,224 @@
+package kingpin
+
+import (
+	"fmt"
+	"strings"
+)
+
+func init() {
+	configDecoders[".yaml"] = decodeYAMLConfig
+	configDecoders[".yml"] = decodeYAMLConfig
+	configDecoders[".toml"] = decodeTOMLConfig
+}
+
+// decodeYAMLConfig implements a pragmatic subset of YAML sufficient for the
+// nested key/value configuration files this package maps flags against:
+// indentation-based mapping nesting, "key: value" scalars, flow-style
+// lists ("key: [a, b]"), and block-style lists ("key:" followed by
+// indented "- item" lines). Anchors, multi-document streams and flow
+// mappings are not supported; register a full decoder with
+// RegisterConfigDecoder if you need them.
+func decodeYAMLConfig(data []byte) (map[string]interface{}, error) {
+	lines := yamlLines(data)
+	node, next, err := parseYAMLMapping(lines, 0, -1)
+	if err != nil {
+		return nil, err
+	}
+	if next < len(lines) {
+		return nil, fmt.Errorf("yaml: line %d: unexpected indentation", lines[next].no)
+	}
+	return node, nil
+}
+
+type yamlLine struct {
+	no     int
+	indent int
+	text   string
+}
+
+// yamlLines strips comments and blank lines, recording each remaining
+// line's original line number (for error messages) and indentation.
+func yamlLines(data []byte) []yamlLine {
+	var out []yamlLine
+	for lineNo, raw := range strings.Split(string(data), "\n") {
+		stripped := stripLineComment(raw)
+		if strings.TrimSpace(stripped) == "" {
+			continue
+		}
+		indent := len(stripped) - len(strings.TrimLeft(stripped, " "))
+		out = append(out, yamlLine{no: lineNo + 1, indent: indent, text: strings.TrimSpace(stripped)})
+	}
+	return out
+}
+
+func isYAMLListItem(text string) bool {
+	return text == "-" || strings.HasPrefix(text, "- ")
+}
+
+// parseYAMLMapping consumes a run of "key: value" lines at a single
+// indentation level starting at lines[i], returning the decoded mapping
+// and the index of the first line that belongs to an enclosing level. For
+// the root call, indent is -1 and is fixed to the first line's indent.
+func parseYAMLMapping(lines []yamlLine, i int, indent int) (map[string]interface{}, int, error) {
+	node := map[string]interface{}{}
+	for i < len(lines) {
+		ln := lines[i]
+		if indent >= 0 && ln.indent != indent {
+			break
+		}
+		if isYAMLListItem(ln.text) {
+			return nil, 0, fmt.Errorf("yaml: line %d: unexpected list item in a mapping", ln.no)
+		}
+		idx := strings.IndexByte(ln.text, ':')
+		if idx < 0 {
+			return nil, 0, fmt.Errorf("yaml: line %d: expected 'key: value'", ln.no)
+		}
+		if indent < 0 {
+			indent = ln.indent
+		}
+		key := strings.TrimSpace(ln.text[:idx])
+		value := strings.TrimSpace(ln.text[idx+1:])
+		i++
+		if value != "" {
+			node[key] = parseFlowScalar(value)
+			continue
+		}
+
+		// An empty value means the key's child is on the following line(s).
+		// A nested mapping is always indented deeper than the key, but a
+		// block-style list item may be indented deeper or, as is
+		// idiomatic for top-level YAML, at the very same indentation as
+		// the key itself; check for a list before treating a line at the
+		// key's own indentation as a sibling key.
+		if i >= len(lines) || lines[i].indent < ln.indent {
+			node[key] = map[string]interface{}{}
+			continue
+		}
+		if isYAMLListItem(lines[i].text) {
+			list, next, err := parseYAMLList(lines, i, lines[i].indent)
+			if err != nil {
+				return nil, 0, err
+			}
+			node[key] = list
+			i = next
+			continue
+		}
+		if lines[i].indent == ln.indent {
+			node[key] = map[string]interface{}{}
+			continue
+		}
+		child, next, err := parseYAMLMapping(lines, i, lines[i].indent)
+		if err != nil {
+			return nil, 0, err
+		}
+		node[key] = child
+		i = next
+	}
+	return node, i, nil
+}
+
+// parseYAMLList consumes a run of "- item" lines at a single indentation
+// level starting at lines[i], returning the decoded list and the index of
+// the first line that belongs to an enclosing level.
+func parseYAMLList(lines []yamlLine, i int, indent int) ([]interface{}, int, error) {
+	var list []interface{}
+	for i < len(lines) && lines[i].indent == indent && isYAMLListItem(lines[i].text) {
+		item := strings.TrimSpace(strings.TrimPrefix(lines[i].text, "-"))
+		if item == "" {
+			return nil, 0, fmt.Errorf("yaml: line %d: empty list item", lines[i].no)
+		}
+		list = append(list, parseFlowScalar(item))
+		i++
+	}
+	return list, i, nil
+}
+
+// decodeTOMLConfig implements a pragmatic subset of TOML: [section] and
+// [section.sub] table headers, and "key = value" scalars or arrays within
+// them. Inline tables, dotted keys outside of headers, and datetimes are
+// not supported; register a full decoder with RegisterConfigDecoder if you
+// need them.
+func decodeTOMLConfig(data []byte) (map[string]interface{}, error) {
+	root := map[string]interface{}{}
+	current := root
+
+	for lineNo, raw := range strings.Split(string(data), "\n") {
+		line := strings.TrimSpace(stripLineComment(raw))
+		if line == "" {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			path := strings.TrimSpace(line[1 : len(line)-1])
+			current = root
+			for _, key := range strings.Split(path, ".") {
+				key = strings.TrimSpace(key)
+				child, ok := current[key].(map[string]interface{})
+				if !ok {
+					child = map[string]interface{}{}
+					current[key] = child
+				}
+				current = child
+			}
+			continue
+		}
+
+		idx := strings.IndexByte(line, '=')
+		if idx < 0 {
+			return nil, fmt.Errorf("toml: line %d: expected 'key = value'", lineNo+1)
+		}
+		key := strings.TrimSpace(line[:idx])
+		value := strings.TrimSpace(line[idx+1:])
+		current[key] = parseFlowScalar(value)
+	}
+	return root, nil
+}
+
+// parseFlowScalar decodes a scalar or a flow-style list ("[a, b, c]"),
+// shared by the YAML and TOML decoders above.
+func parseFlowScalar(value string) interface{} {
+	if strings.HasPrefix(value, "[") && strings.HasSuffix(value, "]") {
+		inner := strings.TrimSpace(value[1 : len(value)-1])
+		if inner == "" {
+			return []interface{}{}
+		}
+		parts := strings.Split(inner, ",")
+		list := make([]interface{}, len(parts))
+		for i, part := range parts {
+			list[i] = parseFlowScalar(strings.TrimSpace(part))
+		}
+		return list
+	}
+	return unquote(value)
+}
+
+func unquote(value string) string {
+	if len(value) >= 2 {
+		if (value[0] == '"' && value[len(value)-1] == '"') || (value[0] == '\'' && value[len(value)-1] == '\'') {
+			return value[1 : len(value)-1]
+		}
+	}
+	return value
+}
+
+// stripLineComment removes a trailing "# ..." comment, ignoring '#' inside
+// quoted strings.
+func stripLineComment(line string) string {
+	var inQuote byte
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		if inQuote != 0 {
+			if c == inQuote {
+				inQuote = 0
+			}
+			continue
+		}
+		switch c {
+		case '"', '\'':
+			inQuote = c
+		case '#':
+			return line[:i]
+		}
+	}
+	return line
+}
@@ -0,0 +1,64 @@
+package kingpin
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// Counter sets the parser to a counter: each occurrence of the flag
+// increments the target by one (-v -v -v, or bundled as -vvv, both give 3).
+// Like a bool flag it takes no argument, so it combines with Short bundling.
+func (p *parserMixin) Counter() (target *int) {
+	target = new(int)
+	p.CounterVar(target)
+	return
+}
+
+// CounterVar is like Counter but stores into an existing variable.
+func (p *parserMixin) CounterVar(target *int) {
+	p.SetValue(newCounterValue(target))
+}
+
+// -- counter Value
+
+type counterValue int
+
+func newCounterValue(n *int) *counterValue {
+	return (*counterValue)(n)
+}
+
+func (c *counterValue) Set(value string) error {
+	switch value {
+	case "true":
+		*c++
+	case "false":
+		*c--
+	default:
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("'%s' is not a valid value for a counter flag", value)
+		}
+		*c = counterValue(n)
+	}
+	return nil
+}
+
+func (c *counterValue) Get() interface{} {
+	return (int)(*c)
+}
+
+func (c *counterValue) String() string {
+	return strconv.Itoa(int(*c))
+}
+
+// IsBoolFlag makes a counter flag accept no argument on the command line,
+// each occurrence instead incrementing the count.
+func (c *counterValue) IsBoolFlag() bool {
+	return true
+}
+
+// IsCumulative marks a counter as repeatable rather than overwritten by a
+// second occurrence.
+func (c *counterValue) IsCumulative() bool {
+	return true
+}
@@ -0,0 +1,55 @@
+package kingpin
+
+import "testing"
+
+func TestCounterValueSet(t *testing.T) {
+	var n int
+	c := newCounterValue(&n)
+
+	// -v -v -v: each bare occurrence is delivered as "true" and increments.
+	for i := 0; i < 3; i++ {
+		if err := c.Set("true"); err != nil {
+			t.Fatalf("Set(true) #%d: unexpected error: %s", i, err)
+		}
+	}
+	if n != 3 {
+		t.Fatalf("after 3x Set(true), n = %d, want 3", n)
+	}
+
+	if err := c.Set("false"); err != nil {
+		t.Fatalf("Set(false): unexpected error: %s", err)
+	}
+	if n != 2 {
+		t.Fatalf("after Set(false), n = %d, want 2", n)
+	}
+
+	if err := c.Set("10"); err != nil {
+		t.Fatalf("Set(10): unexpected error: %s", err)
+	}
+	if n != 10 {
+		t.Fatalf("after Set(10), n = %d, want 10", n)
+	}
+
+	if err := c.Set("not-a-number"); err == nil {
+		t.Fatalf("Set(not-a-number): expected error, got none")
+	}
+}
+
+func TestCounterValueFlags(t *testing.T) {
+	var n int
+	c := newCounterValue(&n)
+	if !c.IsBoolFlag() {
+		t.Error("IsBoolFlag() = false, want true (counters take no argument)")
+	}
+	if !c.IsCumulative() {
+		t.Error("IsCumulative() = false, want true (repeated occurrences accumulate)")
+	}
+}
+
+func TestCounterValueString(t *testing.T) {
+	n := 3
+	c := newCounterValue(&n)
+	if got, want := c.String(), "3"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
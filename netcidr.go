@@ -0,0 +1,131 @@
+package kingpin
+
+import (
+	"fmt"
+	"net"
+	"strings"
+)
+
+// CIDR sets the parser to a net.IPNet parser, e.g. "10.0.0.0/8".
+func (p *parserMixin) CIDR() (target **net.IPNet) {
+	target = new(*net.IPNet)
+	p.CIDRVar(target)
+	return
+}
+
+// CIDRVar sets the parser to a net.IPNet parser.
+func (p *parserMixin) CIDRVar(target **net.IPNet) {
+	p.SetValue(newCIDRValue(target))
+}
+
+// CIDRList accumulates net.IPNet values into a slice.
+func (p *parserMixin) CIDRList() (target *[]*net.IPNet) {
+	target = new([]*net.IPNet)
+	p.CIDRListVar(target)
+	return
+}
+
+// CIDRListVar accumulates net.IPNet values into a slice.
+func (p *parserMixin) CIDRListVar(target *[]*net.IPNet) {
+	p.SetValue(newCIDRsValue(target))
+}
+
+// HardwareAddr sets the parser to a net.HardwareAddr (MAC address) parser.
+func (p *parserMixin) HardwareAddr() (target *net.HardwareAddr) {
+	target = new(net.HardwareAddr)
+	p.HardwareAddrVar(target)
+	return
+}
+
+// HardwareAddrVar sets the parser to a net.HardwareAddr (MAC address) parser.
+func (p *parserMixin) HardwareAddrVar(target *net.HardwareAddr) {
+	p.SetValue(newHardwareAddrValue(target))
+}
+
+// -- net.IPNet Value
+
+type cidrValue struct {
+	ipNet **net.IPNet
+}
+
+func newCIDRValue(target **net.IPNet) *cidrValue {
+	return &cidrValue{target}
+}
+
+func (c *cidrValue) Set(value string) error {
+	_, ipNet, err := net.ParseCIDR(value)
+	if err != nil {
+		return fmt.Errorf("'%s' is not a valid CIDR: %s", value, err)
+	}
+	*c.ipNet = ipNet
+	return nil
+}
+
+func (c *cidrValue) Get() interface{} {
+	return (*net.IPNet)(*c.ipNet)
+}
+
+func (c *cidrValue) String() string {
+	if *c.ipNet == nil {
+		return ""
+	}
+	return (*c.ipNet).String()
+}
+
+// -- []*net.IPNet Value
+
+type cidrsValue []*net.IPNet
+
+func newCIDRsValue(slice *[]*net.IPNet) *cidrsValue {
+	return (*cidrsValue)(slice)
+}
+
+func (c *cidrsValue) Set(value string) error {
+	_, ipNet, err := net.ParseCIDR(value)
+	if err != nil {
+		return fmt.Errorf("'%s' is not a valid CIDR: %s", value, err)
+	}
+	*c = append(*c, ipNet)
+	return nil
+}
+
+func (c *cidrsValue) Get() interface{} {
+	return ([]*net.IPNet)(*c)
+}
+
+func (c *cidrsValue) String() string {
+	parts := make([]string, len(*c))
+	for i, n := range *c {
+		parts[i] = n.String()
+	}
+	return strings.Join(parts, ",")
+}
+
+func (c *cidrsValue) IsCumulative() bool {
+	return true
+}
+
+// -- net.HardwareAddr Value
+
+type hardwareAddrValue net.HardwareAddr
+
+func newHardwareAddrValue(target *net.HardwareAddr) *hardwareAddrValue {
+	return (*hardwareAddrValue)(target)
+}
+
+func (h *hardwareAddrValue) Set(value string) error {
+	addr, err := net.ParseMAC(value)
+	if err != nil {
+		return fmt.Errorf("'%s' is not a valid MAC address: %s", value, err)
+	}
+	*h = hardwareAddrValue(addr)
+	return nil
+}
+
+func (h *hardwareAddrValue) Get() interface{} {
+	return (net.HardwareAddr)(*h)
+}
+
+func (h *hardwareAddrValue) String() string {
+	return (net.HardwareAddr)(*h).String()
+}
@@ -0,0 +1,59 @@
+package kingpin
+
+import (
+	"net"
+	"testing"
+)
+
+func TestCIDRValueSet(t *testing.T) {
+	var target *net.IPNet
+	c := newCIDRValue(&target)
+
+	if err := c.Set("10.0.0.0/8"); err != nil {
+		t.Fatalf("Set(10.0.0.0/8): unexpected error: %s", err)
+	}
+	if target == nil || target.String() != "10.0.0.0/8" {
+		t.Errorf("Set(10.0.0.0/8): target = %v, want 10.0.0.0/8", target)
+	}
+
+	if err := c.Set("not-a-cidr"); err == nil {
+		t.Error("Set(not-a-cidr): expected error, got none")
+	}
+}
+
+func TestCIDRsValueSetAccumulates(t *testing.T) {
+	var target []*net.IPNet
+	c := newCIDRsValue(&target)
+
+	if err := c.Set("10.0.0.0/8"); err != nil {
+		t.Fatalf("Set(10.0.0.0/8): unexpected error: %s", err)
+	}
+	if err := c.Set("192.168.0.0/16"); err != nil {
+		t.Fatalf("Set(192.168.0.0/16): unexpected error: %s", err)
+	}
+	if len(target) != 2 {
+		t.Fatalf("len(target) = %d, want 2", len(target))
+	}
+	if !c.IsCumulative() {
+		t.Error("IsCumulative() = false, want true")
+	}
+	if got, want := c.String(), "10.0.0.0/8,192.168.0.0/16"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
+
+func TestHardwareAddrValueSet(t *testing.T) {
+	var target net.HardwareAddr
+	h := newHardwareAddrValue(&target)
+
+	if err := h.Set("01:23:45:67:89:ab"); err != nil {
+		t.Fatalf("Set: unexpected error: %s", err)
+	}
+	if got, want := h.String(), "01:23:45:67:89:ab"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+
+	if err := h.Set("not-a-mac"); err == nil {
+		t.Error("Set(not-a-mac): expected error, got none")
+	}
+}
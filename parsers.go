@@ -12,14 +12,46 @@ type Settings interface {
 }
 
 type parserMixin struct {
-	value    Value
-	required bool
+	value      Value
+	required   bool
+	configPath string
+	envar      string
+	name       string
+	short      byte
+	help       string
 }
 
 func (p *parserMixin) SetValue(value Value) {
 	p.value = value
 }
 
+// Envar sets the name of an environment variable whose value, if set, is
+// used when the flag is not given on the command line, ahead of its
+// ConfigPath (if any) and its default. See Application.ConfigFile for the
+// full precedence order.
+func (p *parserMixin) Envar(name string) *parserMixin {
+	p.envar = name
+	return p
+}
+
+// Short sets a one-letter short form for this flag, usable on the command
+// line as -x, or bundled with other short flags (e.g. -vvv for three
+// occurrences of a flag with IsBoolFlag/IsCumulative semantics).
+func (p *parserMixin) Short(name byte) *parserMixin {
+	p.short = name
+	return p
+}
+
+// ConfigPath maps this flag to a dotted key (e.g. "server.listen.addr") in
+// the application's configuration file. When the flag is not given on the
+// command line or through its environment variable (see Envar), the value
+// at this path is used instead, ahead of the flag's default. See
+// Application.ConfigFile.
+func (p *parserMixin) ConfigPath(path string) *parserMixin {
+	p.configPath = path
+	return p
+}
+
 // String sets the parser to a string parser.
 func (p *parserMixin) String() (target *string) {
 	target = new(string)
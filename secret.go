@@ -0,0 +1,89 @@
+package kingpin
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+)
+
+// Secret sets the parser to a string parser whose value is redacted (shown
+// as "****") in --help, usage output and --dump-config, for credentials
+// that shouldn't be echoed back to the user.
+func (p *parserMixin) Secret() (target *string) {
+	target = new(string)
+	p.SecretVar(target)
+	return
+}
+
+// SecretVar is like Secret but stores into an existing variable.
+func (p *parserMixin) SecretVar(target *string) {
+	p.SetValue(newRedactedValue(newStringValue("", target)))
+}
+
+// SecretFile is like Secret but reads the value from the file at the given
+// path, or from stdin if the path is "-", analogous to Docker/Kubernetes'
+// --password-stdin.
+func (p *parserMixin) SecretFile() (target *string) {
+	target = new(string)
+	p.SecretFileVar(target)
+	return
+}
+
+// SecretFileVar is like SecretFile but stores into an existing variable.
+func (p *parserMixin) SecretFileVar(target *string) {
+	p.SetValue(newRedactedValue(newSecretFileValue(target)))
+}
+
+// -- redacted Value wrapper
+
+// redactedValue wraps another Value, hiding its contents behind "****"
+// wherever it's rendered for display, while Set and Get still go through to
+// the real value.
+type redactedValue struct {
+	Value
+}
+
+func newRedactedValue(v Value) *redactedValue {
+	return &redactedValue{v}
+}
+
+func (r *redactedValue) String() string {
+	if r.Value.String() == "" {
+		return ""
+	}
+	return "****"
+}
+
+// -- secret-from-file Value
+
+type secretFileValue struct {
+	target *string
+}
+
+func newSecretFileValue(target *string) *secretFileValue {
+	return &secretFileValue{target}
+}
+
+func (s *secretFileValue) Set(path string) error {
+	var data []byte
+	var err error
+	if path == "-" {
+		data, err = ioutil.ReadAll(os.Stdin)
+	} else {
+		data, err = ioutil.ReadFile(path)
+	}
+	if err != nil {
+		return fmt.Errorf("reading secret from '%s': %s", path, err)
+	}
+	*s.target = strings.TrimRight(string(data), "\r\n")
+	return nil
+}
+
+func (s *secretFileValue) Get() interface{} {
+	return *s.target
+}
+
+func (s *secretFileValue) String() string {
+	return *s.target
+}
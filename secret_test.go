@@ -0,0 +1,72 @@
+package kingpin
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRedactedValueHidesNonEmptyValue(t *testing.T) {
+	var target string
+	r := newRedactedValue(newStringValue("", &target))
+
+	if err := r.Set("hunter2"); err != nil {
+		t.Fatalf("Set: unexpected error: %s", err)
+	}
+	if target != "hunter2" {
+		t.Errorf("target = %q, want %q (Set must still write through)", target, "hunter2")
+	}
+	if got, want := r.String(), "****"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
+
+func TestRedactedValueLeavesEmptyUnredacted(t *testing.T) {
+	var target string
+	r := newRedactedValue(newStringValue("", &target))
+
+	if got, want := r.String(), ""; got != want {
+		t.Errorf("String() = %q, want %q for an unset secret", got, want)
+	}
+}
+
+func TestSecretFileValueReadsFromFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "token")
+	if err := os.WriteFile(path, []byte("s3cr3t\n"), 0o600); err != nil {
+		t.Fatalf("writing fixture: %s", err)
+	}
+
+	var target string
+	s := newSecretFileValue(&target)
+	if err := s.Set(path); err != nil {
+		t.Fatalf("Set(%s): unexpected error: %s", path, err)
+	}
+	if got, want := target, "s3cr3t"; got != want {
+		t.Errorf("target = %q, want %q (trailing newline should be trimmed)", got, want)
+	}
+}
+
+func TestSecretFileValueReadsFromStdin(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("creating pipe: %s", err)
+	}
+	origStdin := os.Stdin
+	os.Stdin = r
+	defer func() { os.Stdin = origStdin }()
+
+	go func() {
+		_, _ = w.WriteString("from-stdin\n")
+		w.Close()
+	}()
+
+	var target string
+	s := newSecretFileValue(&target)
+	if err := s.Set("-"); err != nil {
+		t.Fatalf("Set(-): unexpected error: %s", err)
+	}
+	if got, want := target, "from-stdin"; got != want {
+		t.Errorf("target = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,152 @@
+package kingpin
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+)
+
+// URLWithSchemes is like URL but rejects any URL whose scheme is not one of
+// schemes, e.g. URLWithSchemes("http", "https").
+func (p *parserMixin) URLWithSchemes(schemes ...string) (target **url.URL) {
+	target = new(*url.URL)
+	p.SetValue(newURLValue(target, schemes...))
+	return
+}
+
+// TCPResolvedBy is like TCP but resolves the address with resolve instead
+// of net.ResolveTCPAddr, letting callers inject a custom or mock resolver.
+func (p *parserMixin) TCPResolvedBy(resolve func(address string) (*net.TCPAddr, error)) (target **net.TCPAddr) {
+	target = new(*net.TCPAddr)
+	p.SetValue(&tcpAddrValue{addr: target, resolve: resolve})
+	return
+}
+
+// TCPListResolvedBy is like TCPList but resolves each address with resolve
+// instead of net.ResolveTCPAddr.
+func (p *parserMixin) TCPListResolvedBy(resolve func(address string) (*net.TCPAddr, error)) (target *[]*net.TCPAddr) {
+	target = new([]*net.TCPAddr)
+	p.SetValue(&tcpAddrsValue{addrs: target, resolve: resolve})
+	return
+}
+
+// -- url.URL Value
+
+type urlValue struct {
+	url     **url.URL
+	schemes map[string]bool
+}
+
+func newURLValue(target **url.URL, schemes ...string) *urlValue {
+	v := &urlValue{url: target}
+	if len(schemes) > 0 {
+		v.schemes = make(map[string]bool, len(schemes))
+		for _, s := range schemes {
+			v.schemes[s] = true
+		}
+	}
+	return v
+}
+
+func (u *urlValue) Set(value string) error {
+	parsed, err := url.Parse(value)
+	if err != nil {
+		return fmt.Errorf("invalid URL: %s", err)
+	}
+	if u.schemes != nil && !u.schemes[parsed.Scheme] {
+		return fmt.Errorf("URL scheme '%s' is not one of %s", parsed.Scheme, strings.Join(u.schemeList(), ", "))
+	}
+	*u.url = parsed
+	return nil
+}
+
+func (u *urlValue) schemeList() []string {
+	list := make([]string, 0, len(u.schemes))
+	for s := range u.schemes {
+		list = append(list, s)
+	}
+	return list
+}
+
+func (u *urlValue) Get() interface{} {
+	return (*url.URL)(*u.url)
+}
+
+func (u *urlValue) String() string {
+	if *u.url == nil {
+		return ""
+	}
+	return (*u.url).String()
+}
+
+// -- net.TCPAddr Value, with an injectable resolver
+
+func defaultTCPResolve(address string) (*net.TCPAddr, error) {
+	return net.ResolveTCPAddr("tcp", address)
+}
+
+type tcpAddrValue struct {
+	addr    **net.TCPAddr
+	resolve func(address string) (*net.TCPAddr, error)
+}
+
+func newTCPAddrValue(target **net.TCPAddr) *tcpAddrValue {
+	return &tcpAddrValue{addr: target, resolve: defaultTCPResolve}
+}
+
+func (t *tcpAddrValue) Set(value string) error {
+	addr, err := t.resolve(value)
+	if err != nil {
+		return err
+	}
+	*t.addr = addr
+	return nil
+}
+
+func (t *tcpAddrValue) Get() interface{} {
+	return (*net.TCPAddr)(*t.addr)
+}
+
+func (t *tcpAddrValue) String() string {
+	if *t.addr == nil {
+		return ""
+	}
+	return (*t.addr).String()
+}
+
+// -- []*net.TCPAddr Value, with an injectable resolver
+
+type tcpAddrsValue struct {
+	addrs   *[]*net.TCPAddr
+	resolve func(address string) (*net.TCPAddr, error)
+}
+
+func newTCPAddrsValue(target *[]*net.TCPAddr) *tcpAddrsValue {
+	return &tcpAddrsValue{addrs: target, resolve: defaultTCPResolve}
+}
+
+func (t *tcpAddrsValue) Set(value string) error {
+	addr, err := t.resolve(value)
+	if err != nil {
+		return err
+	}
+	*t.addrs = append(*t.addrs, addr)
+	return nil
+}
+
+func (t *tcpAddrsValue) Get() interface{} {
+	return ([]*net.TCPAddr)(*t.addrs)
+}
+
+func (t *tcpAddrsValue) String() string {
+	parts := make([]string, len(*t.addrs))
+	for i, a := range *t.addrs {
+		parts[i] = a.String()
+	}
+	return strings.Join(parts, ",")
+}
+
+func (t *tcpAddrsValue) IsCumulative() bool {
+	return true
+}
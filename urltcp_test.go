@@ -0,0 +1,67 @@
+package kingpin
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"testing"
+)
+
+func TestURLValueSchemeAllowList(t *testing.T) {
+	var target *url.URL
+	u := newURLValue(&target, "http", "https")
+
+	if err := u.Set("https://example.com"); err != nil {
+		t.Fatalf("Set(https://...): unexpected error: %s", err)
+	}
+	if target == nil || target.Scheme != "https" {
+		t.Errorf("target = %v, want scheme https", target)
+	}
+
+	if err := u.Set("ftp://example.com"); err == nil {
+		t.Error("Set(ftp://...): expected error, got none")
+	}
+}
+
+func TestURLValueNoSchemeRestriction(t *testing.T) {
+	var target *url.URL
+	u := newURLValue(&target)
+
+	if err := u.Set("ftp://example.com"); err != nil {
+		t.Fatalf("Set(ftp://...) with no allow-list: unexpected error: %s", err)
+	}
+}
+
+func TestTCPResolvedByInjectsResolver(t *testing.T) {
+	want := &net.TCPAddr{IP: net.ParseIP("127.0.0.1"), Port: 1234}
+	var calledWith string
+	resolve := func(address string) (*net.TCPAddr, error) {
+		calledWith = address
+		return want, nil
+	}
+
+	var target *net.TCPAddr
+	v := &tcpAddrValue{addr: &target, resolve: resolve}
+
+	if err := v.Set("localhost:1234"); err != nil {
+		t.Fatalf("Set: unexpected error: %s", err)
+	}
+	if calledWith != "localhost:1234" {
+		t.Errorf("resolver called with %q, want %q", calledWith, "localhost:1234")
+	}
+	if target != want {
+		t.Errorf("target = %v, want %v", target, want)
+	}
+}
+
+func TestTCPResolvedByPropagatesError(t *testing.T) {
+	resolve := func(address string) (*net.TCPAddr, error) {
+		return nil, fmt.Errorf("mock resolution failure")
+	}
+	var target *net.TCPAddr
+	v := &tcpAddrValue{addr: &target, resolve: resolve}
+
+	if err := v.Set("unreachable:1234"); err == nil {
+		t.Error("Set: expected error from injected resolver, got none")
+	}
+}